@@ -1,51 +1,116 @@
-// Command go-bbs-api is a simple RESTful API for a bulletin board.
-// It uses a pure Go SQLite driver to persist post data.
-package main
-
-import (
-	"database/sql"
-	"fmt"
-	"log"
-	"net/http"
-
-	"github.com/hajime110419/go-bbs-api/internal/handler"
-	"github.com/hajime110419/go-bbs-api/internal/middleware"
-	"github.com/hajime110419/go-bbs-api/internal/repository"
-	"github.com/hajime110419/go-bbs-api/internal/service"
-	"github.com/juju/ratelimit"
-)
-
-var (
-	db *sql.DB
-)
-
-func main() {
-	// Initialize the database connection and table schema.
-	db = repository.InitDB()
-	// Ensure the database connection is closed when the application exits.
-	defer db.Close()
-
-	// Initialize the service layer with the database connection.
-	postService := service.NewPostService(db)
-
-	// Initialize the handler with the service -> presentation layer
-	h := handler.NewPostHandler(postService)
-
-	rate := 2.0
-	capacity := int64(2)
-
-	limiterBucket := ratelimit.NewBucketWithRate(rate, capacity)
-
-	limitedHandler := middleware.RateLimiterMiddleware(limiterBucket)(h.HandlePosts)
-
-	http.HandleFunc("/", h.HandleRoot)
-	http.HandleFunc("/posts", limitedHandler)
-
-	port := ":8080"
-	fmt.Printf("Starting server on port %s…\n", port)
-
-	// Start the HTTP server.
-	if err := http.ListenAndServe(port, nil); err != nil {
-		log.Fatal("Failed to start server:", err)
-	}
-}
+// Command go-bbs-api is a simple RESTful API for a bulletin board.
+// It uses a pure Go SQLite driver to persist post data.
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/hajime110419/go-bbs-api/internal/config"
+	"github.com/hajime110419/go-bbs-api/internal/handler"
+	"github.com/hajime110419/go-bbs-api/internal/middleware"
+	"github.com/hajime110419/go-bbs-api/internal/repository"
+	"github.com/hajime110419/go-bbs-api/internal/service"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func main() {
+	cfg, err := config.Load(os.Getenv("BBS_CONFIG_FILE"))
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Initialize the database connection and bring the schema up to date.
+	db, err := repository.InitDB(cfg.DBPath)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	// Initialize the service layer with the database connection.
+	postService := service.NewPostService(db)
+	userService := service.NewUserService(db)
+	commentService := service.NewCommentService(db)
+
+	// Initialize the handlers with the services -> presentation layer
+	postHandler := handler.NewPostHandler(postService, userService)
+	userHandler := handler.NewUserHandler(userService)
+	commentHandler := handler.NewCommentHandler(commentService, userService)
+
+	// Writes require a valid bearer token identifying the acting user.
+	requireAuth := middleware.AuthMiddleware(userService)
+
+	// Reads and writes are rate limited separately: writes are far more
+	// expensive and abuse-prone than reads, so they get a tighter bucket.
+	readLimiter := middleware.NewRateLimiter(middleware.RateLimitConfig{
+		Rate: cfg.Rate, Capacity: cfg.Capacity, TrustForwarded: cfg.TrustProxy,
+	})
+	writeLimiter := middleware.NewRateLimiter(middleware.RateLimitConfig{
+		Rate: cfg.Rate / 5, Capacity: cfg.Capacity / 3, TrustForwarded: cfg.TrustProxy,
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", postHandler.HandleRoot)
+	mux.HandleFunc("GET /posts", readLimiter.Middleware(postHandler.HandleGetPosts))
+	mux.HandleFunc("POST /posts", writeLimiter.Middleware(requireAuth(postHandler.HandleCreatePost)))
+	mux.HandleFunc("GET /posts/{id}", readLimiter.Middleware(postHandler.HandleGetPost))
+	mux.HandleFunc("PUT /posts/{id}", writeLimiter.Middleware(requireAuth(postHandler.HandleUpdatePost)))
+	mux.HandleFunc("DELETE /posts/{id}", writeLimiter.Middleware(requireAuth(postHandler.HandleDeletePost)))
+	mux.HandleFunc("GET /posts/{id}/comments", readLimiter.Middleware(commentHandler.HandleGetComments))
+	mux.HandleFunc("POST /posts/{id}/comments", writeLimiter.Middleware(requireAuth(commentHandler.HandleCreateComment)))
+	mux.HandleFunc("POST /users", writeLimiter.Middleware(userHandler.HandleRegister))
+
+	// Expose DB pool stats alongside the HTTP counters/histogram recorded
+	// by middleware.Metrics.
+	prometheus.MustRegister(middleware.NewDBStatsCollector(db))
+	mux.Handle("GET /metrics", promhttp.Handler())
+
+	// Logging and metrics wrap every request except CORS preflights, which
+	// CORSMiddleware answers itself, including ones the rate limiter or auth
+	// middleware will go on to reject.
+	handlerChain := middleware.CORSMiddleware(middleware.Logger(middleware.Metrics(mux)))
+
+	server := &http.Server{
+		Addr:         cfg.Port,
+		Handler:      handlerChain,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("starting server", "addr", cfg.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+			return
+		}
+		serverErr <- nil
+	}()
+
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	case <-ctx.Done():
+		stop()
+		slog.Info("shutting down server", "timeout", cfg.ShutdownTimeout)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("server shutdown did not complete cleanly", "error", err)
+		}
+	}
+}
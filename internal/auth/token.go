@@ -0,0 +1,29 @@
+// Package auth provides the primitives used to mint and verify the opaque
+// bearer tokens issued to registered users.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// TokenBytes is the number of random bytes used to generate a new token.
+const TokenBytes = 32
+
+// GenerateToken creates a new cryptographically random opaque bearer token,
+// hex-encoded for safe transport in an Authorization header.
+func GenerateToken() (string, error) {
+	b := make([]byte, TokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashToken returns the SHA-256 hex digest of a token. Only the hash is ever
+// persisted, so a leaked database never exposes usable bearer tokens.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,117 @@
+// Package config loads runtime configuration for the server: an optional
+// YAML file provides defaults for a deployment, and BBS_* environment
+// variables override individual fields on top of that, mirroring the
+// config-file pattern used by the bbj2 and pomme externals.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds everything needed to start the server.
+type Config struct {
+	Port            string        `yaml:"port"`
+	DBPath          string        `yaml:"db_path"`
+	Rate            float64       `yaml:"rate"`
+	Capacity        int64         `yaml:"capacity"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	TrustProxy      bool          `yaml:"trust_proxy"`
+}
+
+// Default returns the configuration used when no file or env vars override it.
+func Default() Config {
+	return Config{
+		Port:            ":8080",
+		DBPath:          "./bulletinboard.db",
+		Rate:            5,
+		Capacity:        10,
+		ReadTimeout:     5 * time.Second,
+		WriteTimeout:    10 * time.Second,
+		ShutdownTimeout: 15 * time.Second,
+		TrustProxy:      false,
+	}
+}
+
+// Load builds a Config starting from Default(), applying the YAML file at
+// path if it exists (a missing file is not an error — the file is
+// optional), then applying BBS_* environment variable overrides.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case os.IsNotExist(err):
+			// No config file is fine; fall back to defaults and env vars.
+		case err != nil:
+			return cfg, fmt.Errorf("failed to read config file: %w", err)
+		default:
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return cfg, fmt.Errorf("failed to parse config file: %w", err)
+			}
+		}
+	}
+
+	if err := applyEnv(&cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// applyEnv overrides cfg with any BBS_* environment variables that are set.
+func applyEnv(cfg *Config) error {
+	if v := os.Getenv("BBS_PORT"); v != "" {
+		cfg.Port = v
+	}
+	if v := os.Getenv("BBS_DB_PATH"); v != "" {
+		cfg.DBPath = v
+	}
+	if v := os.Getenv("BBS_RATE"); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid BBS_RATE: %w", err)
+		}
+		cfg.Rate = rate
+	}
+	if v := os.Getenv("BBS_CAPACITY"); v != "" {
+		capacity, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid BBS_CAPACITY: %w", err)
+		}
+		cfg.Capacity = capacity
+	}
+	if v := os.Getenv("BBS_READ_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid BBS_READ_TIMEOUT: %w", err)
+		}
+		cfg.ReadTimeout = d
+	}
+	if v := os.Getenv("BBS_WRITE_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid BBS_WRITE_TIMEOUT: %w", err)
+		}
+		cfg.WriteTimeout = d
+	}
+	if v := os.Getenv("BBS_SHUTDOWN_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid BBS_SHUTDOWN_TIMEOUT: %w", err)
+		}
+		cfg.ShutdownTimeout = d
+	}
+	if v := os.Getenv("BBS_TRUST_PROXY"); v != "" {
+		cfg.TrustProxy = v == "1"
+	}
+
+	return nil
+}
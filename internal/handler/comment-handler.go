@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/hajime110419/go-bbs-api/internal/middleware"
+	"github.com/hajime110419/go-bbs-api/internal/service"
+)
+
+// CommentHandler exposes the bulletin board's comment endpoints. It
+// delegates all business logic to the service layer.
+type CommentHandler struct {
+	Comments *service.CommentService
+	Users    *service.UserService
+}
+
+// NewCommentHandler creates a new CommentHandler backed by the given services.
+func NewCommentHandler(comments *service.CommentService, users *service.UserService) *CommentHandler {
+	return &CommentHandler{Comments: comments, Users: users}
+}
+
+// HandleGetComments handles GET /posts/{id}/comments. By default it returns
+// a flat, chronologically ordered list the client can reassemble itself;
+// passing ?tree=1 returns the comments nested one level deep under their
+// top-level comment instead.
+func (h *CommentHandler) HandleGetComments(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	postID := r.PathValue("id")
+
+	if r.URL.Query().Get("tree") == "1" {
+		tree, err := h.Comments.GetTree(postID)
+		if err != nil {
+			log.Printf("Failed to retrieve comment tree: %v", err)
+			http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(tree); err != nil {
+			log.Printf("Failed to encode comment tree to JSON: %v", err)
+		}
+		return
+	}
+
+	comments, err := h.Comments.GetByPostID(postID)
+	if err != nil {
+		log.Printf("Failed to retrieve comments: %v", err)
+		http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(comments); err != nil {
+		log.Printf("Failed to encode comments to JSON: %v", err)
+	}
+}
+
+// createCommentRequest is the expected JSON body for POST /posts/{id}/comments.
+type createCommentRequest struct {
+	Content         string  `json:"content"`
+	ParentCommentID *string `json:"parent_comment_id,omitempty"`
+}
+
+// HandleCreateComment handles POST /posts/{id}/comments. It must run behind
+// middleware.AuthMiddleware, which attaches the authenticated author to the
+// request context.
+func (h *CommentHandler) HandleCreateComment(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	var req createCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	author, _ := middleware.UserFromContext(r.Context())
+
+	comment, err := h.Comments.CreateComment(r.PathValue("id"), author.ID, req.Content, req.ParentCommentID)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidComment:
+			http.Error(w, `{"error": "content is required"}`, http.StatusBadRequest)
+		case service.ErrPostNotFound:
+			http.Error(w, `{"error": "Post not found"}`, http.StatusNotFound)
+		default:
+			log.Printf("Failed to create comment: %v", err)
+			http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(comment); err != nil {
+		log.Printf("Failed to encode new comment to JSON: %v", err)
+	}
+}
@@ -1,119 +1,211 @@
-package handler
-
-import (
-	"database/sql"
-	"encoding/json"
-	"fmt"
-	"log"
-	"net/http"
-
-	"github.com/google/uuid" // Used for generating unique IDs for new posts.
-	"github.com/hajime110419/go-bbs-api/internal/models"
-	"github.com/hajime110419/go-bbs-api/internal/utils"
-)
-
-type PostHandler struct {
-	DB *sql.DB
-}
-
-// HandlePosts routes requests for the "/posts" endpoint based on the HTTP method.
-// It also handles CORS preflight (OPTIONS) requests.
-func (h *PostHandler) HandlePosts(w http.ResponseWriter, r *http.Request) {
-	// Set CORS headers to allow cross-origin requests from web browsers.
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	// Handle CORS preflight requests.
-	if r.Method == "OPTIONS" {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	switch r.Method {
-	case "GET":
-		h.HandleGetPosts(w, r)
-	case "POST":
-		h.HandleCreatePost(w, r)
-	default:
-		http.Error(w, `{"error": "Method not allowed"}`, http.StatusMethodNotAllowed)
-	}
-}
-
-// HandleGetPosts handles GET requests to "/posts". It retrieves all posts from
-// the database, ordered by creation time (descending), and returns them as a JSON array.
-func (h *PostHandler) HandleGetPosts(w http.ResponseWriter, r *http.Request) {
-	// "rowid" is an implicit auto-incrementing column in SQLite. Ordering by it
-	// in descending order retrieves the most recent posts first.
-	rows, err := h.DB.Query("SELECT id, title, content FROM posts ORDER BY rowid DESC")
-	if err != nil {
-		log.Printf("Failed to query posts from database: %v", err)
-		http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
-		return
-	}
-	defer rows.Close()
-
-	posts := make([]models.Post, 0)
-	for rows.Next() {
-		var p models.Post
-		if err := rows.Scan(&p.ID, &p.Title, &p.Content); err != nil {
-			log.Printf("Failed to scan row: %v", err)
-			http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
-			return
-		}
-		posts = append(posts, p)
-	}
-
-	if err := json.NewEncoder(w).Encode(posts); err != nil {
-		log.Printf("Failed to encode posts to JSON: %v", err)
-		http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
-	}
-}
-
-// HandleCreatePost handles POST requests to "/posts". It decodes a new post
-// from the request body, assigns a unique ID, sanitizes the input, and inserts
-// it into the database. It returns the newly created post as JSON.
-func (h *PostHandler) HandleCreatePost(w http.ResponseWriter, r *http.Request) {
-	var newPost models.Post
-
-	if err := json.NewDecoder(r.Body).Decode(&newPost); err != nil {
-		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
-		return
-	}
-
-	// Assign a new universally unique identifier (UUID).
-	newPost.ID = uuid.New().String()
-	// Sanitize user-provided title and content to prevent XSS.
-	newPost.Title = utils.Sanitize(newPost.Title)
-	newPost.Content = utils.Sanitize(newPost.Content)
-
-	// Use a prepared statement to prevent SQL injection vulnerabilities.
-	stmt, err := h.DB.Prepare("INSERT INTO posts(id, title, content) VALUES(?, ?, ?)")
-	if err != nil {
-		log.Printf("Failed to prepare statement: %v", err)
-		http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
-		return
-	}
-	defer stmt.Close()
-
-	_, err = stmt.Exec(newPost.ID, newPost.Title, newPost.Content)
-	if err != nil {
-		log.Printf("Failed to insert post into database: %v", err)
-		http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
-		return
-	}
-
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(newPost); err != nil {
-		log.Printf("Failed to encode new post to JSON: %v", err)
-		http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
-	}
-}
-
-// HandleRoot is the handler for the root ("/") endpoint.
-// It returns a simple welcome message.
-func (h *PostHandler) HandleRoot(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	fmt.Fprint(w, "Welcome to the Bulletin Board API! Please use the /posts endpoint.")
-}
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hajime110419/go-bbs-api/internal/middleware"
+	"github.com/hajime110419/go-bbs-api/internal/models"
+	"github.com/hajime110419/go-bbs-api/internal/service"
+)
+
+// v2Accept is the media type clients send in an Accept header to opt into
+// the paginated {"posts": ..., "next_cursor": ...} envelope on GET /posts.
+// Without it, GET /posts keeps returning a bare JSON array for backward
+// compatibility.
+const v2Accept = "application/vnd.bbs.v2+json"
+
+// PostHandler exposes the bulletin board's post endpoints. It delegates all
+// business logic to the service layer.
+type PostHandler struct {
+	Posts *service.PostService
+	Users *service.UserService
+}
+
+// NewPostHandler creates a new PostHandler backed by the given services.
+func NewPostHandler(posts *service.PostService, users *service.UserService) *PostHandler {
+	return &PostHandler{Posts: posts, Users: users}
+}
+
+// postsResponse is the v2 response envelope for GET /posts, returned when
+// the client sends "Accept: application/vnd.bbs.v2+json".
+type postsResponse struct {
+	Posts      []models.Post `json:"posts"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// HandleGetPosts handles GET /posts. It supports keyset pagination
+// (?limit=, ?cursor=), full-text search (?q=), and filtering by author
+// (?author=). Each post is annotated with its author's display name.
+func (h *PostHandler) HandleGetPosts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	query := r.URL.Query()
+
+	limit := service.DefaultPageSize
+	if raw := query.Get("limit"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			limit = n
+		}
+	}
+
+	page, err := h.Posts.ListPosts(service.ListParams{
+		Limit:  limit,
+		Cursor: query.Get("cursor"),
+		Query:  query.Get("q"),
+		Author: query.Get("author"),
+	})
+	if err != nil {
+		switch err {
+		case service.ErrInvalidCursor:
+			http.Error(w, `{"error": "Invalid cursor"}`, http.StatusBadRequest)
+		case service.ErrInvalidQuery:
+			http.Error(w, `{"error": "Invalid search query"}`, http.StatusBadRequest)
+		default:
+			log.Printf("Failed to retrieve posts: %v", err)
+			http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), v2Accept) {
+		resp := postsResponse{Posts: page.Posts, NextCursor: page.NextCursor}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Printf("Failed to encode posts to JSON: %v", err)
+		}
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(page.Posts); err != nil {
+		log.Printf("Failed to encode posts to JSON: %v", err)
+	}
+}
+
+// createPostRequest is the expected JSON body for POST /posts.
+type createPostRequest struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// HandleCreatePost handles POST /posts. It must run behind
+// middleware.AuthMiddleware, which attaches the authenticated author to the
+// request context.
+func (h *PostHandler) HandleCreatePost(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	var req createPostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	author, _ := middleware.UserFromContext(r.Context())
+
+	post, err := h.Posts.CreatePost(req.Title, req.Content, author.ID)
+	if err != nil {
+		if err == service.ErrInvalidPost {
+			http.Error(w, `{"error": "title and content are required"}`, http.StatusBadRequest)
+			return
+		}
+		log.Printf("Failed to create post: %v", err)
+		http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(post); err != nil {
+		log.Printf("Failed to encode new post to JSON: %v", err)
+	}
+}
+
+// HandleGetPost handles GET /posts/{id}.
+func (h *PostHandler) HandleGetPost(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	post, err := h.Posts.GetPost(r.PathValue("id"))
+	if err != nil {
+		if err == service.ErrPostNotFound {
+			http.Error(w, `{"error": "Post not found"}`, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to retrieve post: %v", err)
+		http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(post); err != nil {
+		log.Printf("Failed to encode post to JSON: %v", err)
+	}
+}
+
+// updatePostRequest is the expected JSON body for PUT /posts/{id}.
+type updatePostRequest struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// HandleUpdatePost handles PUT /posts/{id}. It must run behind
+// middleware.AuthMiddleware; only the post's author may update it.
+func (h *PostHandler) HandleUpdatePost(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	var req updatePostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	author, _ := middleware.UserFromContext(r.Context())
+
+	post, err := h.Posts.UpdatePost(r.PathValue("id"), author.ID, req.Title, req.Content)
+	if err != nil {
+		switch err {
+		case service.ErrPostNotFound:
+			http.Error(w, `{"error": "Post not found"}`, http.StatusNotFound)
+		case service.ErrForbidden:
+			http.Error(w, `{"error": "Not authorized to modify this post"}`, http.StatusForbidden)
+		case service.ErrInvalidPost:
+			http.Error(w, `{"error": "title and content are required"}`, http.StatusBadRequest)
+		default:
+			log.Printf("Failed to update post: %v", err)
+			http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(post); err != nil {
+		log.Printf("Failed to encode updated post to JSON: %v", err)
+	}
+}
+
+// HandleDeletePost handles DELETE /posts/{id}. It must run behind
+// middleware.AuthMiddleware; only the post's author may delete it.
+func (h *PostHandler) HandleDeletePost(w http.ResponseWriter, r *http.Request) {
+	author, _ := middleware.UserFromContext(r.Context())
+
+	err := h.Posts.DeletePost(r.PathValue("id"), author.ID)
+	if err != nil {
+		switch err {
+		case service.ErrPostNotFound:
+			http.Error(w, `{"error": "Post not found"}`, http.StatusNotFound)
+		case service.ErrForbidden:
+			http.Error(w, `{"error": "Not authorized to modify this post"}`, http.StatusForbidden)
+		default:
+			log.Printf("Failed to delete post: %v", err)
+			http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleRoot is the handler for the root ("/") endpoint.
+// It returns a simple welcome message.
+func (h *PostHandler) HandleRoot(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, "Welcome to the Bulletin Board API! Please use the /posts endpoint.")
+}
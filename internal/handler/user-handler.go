@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/hajime110419/go-bbs-api/internal/service"
+)
+
+// UserHandler exposes the bulletin board's user registration endpoint.
+type UserHandler struct {
+	Users *service.UserService
+}
+
+// NewUserHandler creates a new UserHandler backed by the given service.
+func NewUserHandler(users *service.UserService) *UserHandler {
+	return &UserHandler{Users: users}
+}
+
+// registerRequest is the expected JSON body for POST /users.
+type registerRequest struct {
+	Username string `json:"username"`
+}
+
+// registerResponse is returned on successful registration. The token is only
+// ever returned here; the server persists just its hash.
+type registerResponse struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Token    string `json:"token"`
+}
+
+// HandleRegister handles POST /users. It creates a new user account and
+// returns a freshly minted bearer token the client must send as
+// "Authorization: Bearer <token>" when creating posts.
+func (h *UserHandler) HandleRegister(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	var req registerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	user, token, err := h.Users.Register(req.Username)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidUser:
+			http.Error(w, `{"error": "username is required"}`, http.StatusBadRequest)
+		case service.ErrUsernameTaken:
+			http.Error(w, `{"error": "username is already taken"}`, http.StatusConflict)
+		default:
+			log.Printf("Failed to register user: %v", err)
+			http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	resp := registerResponse{ID: user.ID, Username: user.Username, Token: token}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("Failed to encode new user to JSON: %v", err)
+	}
+}
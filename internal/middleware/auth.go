@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/hajime110419/go-bbs-api/internal/models"
+)
+
+// UserAuthenticator looks up the user associated with a bearer token.
+type UserAuthenticator interface {
+	Authenticate(token string) (*models.User, error)
+}
+
+// userContextKey is the context key under which AuthMiddleware stores the
+// authenticated user.
+type userContextKey struct{}
+
+// AuthMiddleware requires an "Authorization: Bearer <token>" header, replying
+// 401 when it is missing and 403 when the token does not match a known user.
+// On success the authenticated user is attached to the request context and
+// can be retrieved with UserFromContext.
+func AuthMiddleware(auth UserAuthenticator) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			const prefix = "Bearer "
+
+			header := r.Header.Get("Authorization")
+			if !strings.HasPrefix(header, prefix) {
+				http.Error(w, `{"error": "Missing bearer token"}`, http.StatusUnauthorized)
+				return
+			}
+
+			user, err := auth.Authenticate(strings.TrimPrefix(header, prefix))
+			if err != nil {
+				log.Printf("Failed to authenticate request: %v", err)
+				http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+				return
+			}
+			if user == nil {
+				http.Error(w, `{"error": "Invalid token"}`, http.StatusForbidden)
+				return
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), userContextKey{}, user)))
+		}
+	}
+}
+
+// UserFromContext retrieves the user attached by AuthMiddleware, if any.
+func UserFromContext(ctx context.Context) (*models.User, bool) {
+	u, ok := ctx.Value(userContextKey{}).(*models.User)
+	return u, ok
+}
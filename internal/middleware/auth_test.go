@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hajime110419/go-bbs-api/internal/models"
+)
+
+// fakeAuthenticator is a stub UserAuthenticator for testing AuthMiddleware.
+type fakeAuthenticator struct {
+	user *models.User
+	err  error
+}
+
+func (f fakeAuthenticator) Authenticate(token string) (*models.User, error) {
+	return f.user, f.err
+}
+
+func TestAuthMiddleware_MissingBearerToken(t *testing.T) {
+	next := func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("next should not be called when the bearer token is missing")
+	}
+
+	handler := AuthMiddleware(fakeAuthenticator{})(next)
+
+	req := httptest.NewRequest(http.MethodPost, "/posts", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestAuthMiddleware_InvalidToken(t *testing.T) {
+	next := func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("next should not be called for an invalid token")
+	}
+
+	handler := AuthMiddleware(fakeAuthenticator{user: nil})(next)
+
+	req := httptest.NewRequest(http.MethodPost, "/posts", nil)
+	req.Header.Set("Authorization", "Bearer bad-token")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rec.Code)
+	}
+}
+
+func TestAuthMiddleware_AuthenticateError(t *testing.T) {
+	next := func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("next should not be called when authentication errors")
+	}
+
+	handler := AuthMiddleware(fakeAuthenticator{err: errors.New("db unavailable")})(next)
+
+	req := httptest.NewRequest(http.MethodPost, "/posts", nil)
+	req.Header.Set("Authorization", "Bearer some-token")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+}
+
+func TestAuthMiddleware_ValidToken(t *testing.T) {
+	want := &models.User{ID: "user-1", Username: "alice"}
+
+	var got *models.User
+	next := func(w http.ResponseWriter, r *http.Request) {
+		got, _ = UserFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}
+
+	handler := AuthMiddleware(fakeAuthenticator{user: want})(next)
+
+	req := httptest.NewRequest(http.MethodPost, "/posts", nil)
+	req.Header.Set("Authorization", "Bearer good-token")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if got != want {
+		t.Fatalf("expected user %+v attached to context, got %+v", want, got)
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// requestLogger writes request log lines as JSON to stderr.
+var requestLogger = slog.New(slog.NewJSONHandler(os.Stderr, nil))
+
+// Logger wraps a handler to emit one structured JSON log line per request
+// via log/slog, recording the method, path, status, duration, remote
+// address, and bytes written. It reuses the client's X-Request-ID header
+// when present, otherwise mints one and injects it into the response so
+// callers can correlate logs across a request's lifetime.
+func Logger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		w.Header().Set("X-Request-ID", requestID)
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+		duration := time.Since(start)
+
+		requestLogger.Info("http request",
+			"request_id", requestID,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration_ms", duration.Milliseconds(),
+			"remote_addr", r.RemoteAddr,
+			"bytes_written", rec.bytesWritten,
+		)
+	})
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bbs_http_requests_total",
+		Help: "Total number of HTTP requests processed.",
+	}, []string{"method", "path", "code"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "bbs_http_request_duration_seconds",
+		Help: "Latency of HTTP requests in seconds.",
+	}, []string{"method", "path"})
+)
+
+// Metrics wraps a handler to record bbs_http_requests_total and
+// bbs_http_request_duration_seconds for every request. It should be exposed
+// to Prometheus via promhttp.Handler() on a "/metrics" route.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+		duration := time.Since(start)
+
+		// Label on the matched route template (e.g. "GET /posts/{id}"), set
+		// by ServeMux once it routes the request, rather than r.URL.Path:
+		// the concrete path contains raw IDs, which would give every post
+		// or comment its own time series and grow metric cardinality
+		// without bound.
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+
+		httpRequestsTotal.WithLabelValues(r.Method, route, strconv.Itoa(rec.status)).Inc()
+		httpRequestDuration.WithLabelValues(r.Method, route).Observe(duration.Seconds())
+	})
+}
+
+// dbStatsCollector exposes database/sql connection pool stats as Prometheus
+// gauges (plus a counter for the cumulative wait count), sourced directly
+// from sql.DB.Stats() on every scrape.
+type dbStatsCollector struct {
+	db *sql.DB
+
+	openConnections *prometheus.Desc
+	inUse           *prometheus.Desc
+	idle            *prometheus.Desc
+	waitCount       *prometheus.Desc
+}
+
+// NewDBStatsCollector creates a Prometheus collector for db's connection
+// pool stats. The caller must register it with prometheus.MustRegister.
+func NewDBStatsCollector(db *sql.DB) prometheus.Collector {
+	return &dbStatsCollector{
+		db:              db,
+		openConnections: prometheus.NewDesc("bbs_db_open_connections", "Number of established connections to the database.", nil, nil),
+		inUse:           prometheus.NewDesc("bbs_db_connections_in_use", "Number of connections currently in use.", nil, nil),
+		idle:            prometheus.NewDesc("bbs_db_connections_idle", "Number of idle connections.", nil, nil),
+		waitCount:       prometheus.NewDesc("bbs_db_connections_wait_total", "Total number of connections that had to wait for a free one.", nil, nil),
+	}
+}
+
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.openConnections
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+}
+
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.db.Stats()
+	ch <- prometheus.MustNewConstMetric(c.openConnections, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+}
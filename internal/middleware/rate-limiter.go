@@ -1,26 +1,125 @@
-package middleware
-
-import (
-	"net/http"
-
-	"github.com/juju/ratelimit"
-)
-
-// RateLimiterMiddleware returns an HTTP middleware that applies a rate limit
-// using the juju/ratelimit token bucket.
-func RateLimiterMiddleware(bucket *ratelimit.Bucket) func(http.HandlerFunc) http.HandlerFunc {
-	return func(next http.HandlerFunc) http.HandlerFunc {
-		return func(w http.ResponseWriter, r *http.Request) {
-			// Check if a token is available. TakeAvailable(1) attempts to consume 1 token
-			// immediately and returns 0 if none are avaliable.
-			if bucket.TakeAvailable(1) == 0 {
-				// If rate limit is exceeded, return 429 Too Many Requests.
-				w.Header().Set("Content-Type", "application/json; charset=utf-8")
-				http.Error(w, `{"error": "Too many requests. Please try again later."}`, http.StatusTooManyRequests)
-				return
-			}
-			// If a token is consumed, proceed to the next handler.
-			next(w, r)
-		}
-	}
-}
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/ratelimit"
+)
+
+// idleEvictAfter is how long a per-key bucket may sit unused before it is
+// evicted, so memory stays bounded as clients come and go.
+const idleEvictAfter = 10 * time.Minute
+
+// RateLimitConfig configures the rate/capacity for one class of routes
+// (e.g. reads vs writes), and whether X-Forwarded-For should be trusted
+// when deriving a per-IP key (only safe behind a trusted reverse proxy).
+type RateLimitConfig struct {
+	Rate           float64
+	Capacity       int64
+	TrustForwarded bool
+}
+
+type bucketEntry struct {
+	bucket   *ratelimit.Bucket
+	lastUsed time.Time
+}
+
+// RateLimiter is a keyed token-bucket limiter: each bearer token or, lacking
+// one, each remote IP gets its own bucket, so a single noisy client can't
+// starve everyone else.
+type RateLimiter struct {
+	cfg     RateLimitConfig
+	mu      sync.Mutex
+	buckets map[string]*bucketEntry
+}
+
+// NewRateLimiter creates a RateLimiter for the given config and starts the
+// background goroutine that evicts buckets idle for longer than 10 minutes.
+func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
+	rl := &RateLimiter{
+		cfg:     cfg,
+		buckets: make(map[string]*bucketEntry),
+	}
+	go rl.evictIdle()
+	return rl
+}
+
+// evictIdle periodically removes buckets that haven't been touched in
+// idleEvictAfter, so long-running servers don't accumulate one bucket per
+// client forever.
+func (rl *RateLimiter) evictIdle() {
+	ticker := time.NewTicker(idleEvictAfter)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		rl.mu.Lock()
+		for key, entry := range rl.buckets {
+			if time.Since(entry.lastUsed) > idleEvictAfter {
+				delete(rl.buckets, key)
+			}
+		}
+		rl.mu.Unlock()
+	}
+}
+
+// keyFor derives the limiter key for a request: the bearer token when
+// present, otherwise the client's remote address (honoring
+// X-Forwarded-For when the limiter is configured to trust it).
+func (rl *RateLimiter) keyFor(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return "token:" + strings.TrimPrefix(header, "Bearer ")
+	}
+
+	if rl.cfg.TrustForwarded {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			return "ip:" + strings.TrimSpace(strings.Split(fwd, ",")[0])
+		}
+	}
+
+	return "ip:" + r.RemoteAddr
+}
+
+// bucketFor returns the bucket for key, lazily creating it on first use.
+func (rl *RateLimiter) bucketFor(key string) *ratelimit.Bucket {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	entry, ok := rl.buckets[key]
+	if !ok {
+		entry = &bucketEntry{bucket: ratelimit.NewBucketWithRate(rl.cfg.Rate, rl.cfg.Capacity)}
+		rl.buckets[key] = entry
+	}
+	entry.lastUsed = time.Now()
+
+	return entry.bucket
+}
+
+// Middleware returns an HTTP middleware that applies this limiter's
+// per-key token bucket, setting X-RateLimit-Limit, X-RateLimit-Remaining,
+// and (once exhausted) Retry-After on every response.
+func (rl *RateLimiter) Middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bucket := rl.bucketFor(rl.keyFor(r))
+
+		w.Header().Set("X-RateLimit-Limit", strconv.FormatInt(rl.cfg.Capacity, 10))
+
+		// TakeAvailable(1) attempts to consume 1 token immediately and
+		// returns 0 if none are available.
+		if bucket.TakeAvailable(1) == 0 {
+			retryAfter := int(math.Ceil(1 / rl.cfg.Rate))
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			http.Error(w, `{"error": "Too many requests. Please try again later."}`, http.StatusTooManyRequests)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(bucket.Available(), 10))
+		next(w, r)
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import "net/http"
+
+// responseRecorder wraps an http.ResponseWriter to capture the status code
+// and byte count written by a handler, for middleware that needs to report
+// on the response after the fact (logging, metrics).
+type responseRecorder struct {
+	http.ResponseWriter
+	status       int
+	bytesWritten int
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytesWritten += n
+	return n, err
+}
@@ -0,0 +1,13 @@
+package models
+
+// Comment represents a single reply on a post's comment thread. Comments may
+// nest one level deep via ParentCommentID.
+type Comment struct {
+	ID              string     `json:"id"`
+	PostID          string     `json:"post_id"`
+	AuthorID        string     `json:"author_id"`
+	Content         string     `json:"content"`
+	CreatedAt       string     `json:"created_at"`
+	ParentCommentID *string    `json:"parent_comment_id,omitempty"`
+	Replies         []*Comment `json:"replies,omitempty"`
+}
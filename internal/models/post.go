@@ -1,8 +1,13 @@
 package models
 
-// Post represents a single entry on the bulletin board.
+// Post represents a single entry on the bulletin board. Content holds
+// sanitized HTML rendered from ContentRaw, which is preserved so the post
+// can be edited without lossy round-tripping through HTML.
 type Post struct {
-	ID      string `json:"id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Content    string `json:"content"`
+	ContentRaw string `json:"content_raw,omitempty"`
+	AuthorID   string `json:"-"`
+	Author     string `json:"author,omitempty"`
 }
@@ -0,0 +1,7 @@
+package models
+
+// User represents a registered bulletin board account.
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/hajime110419/go-bbs-api/internal/models"
+)
+
+// CommentRepository handles all database operations for comments.
+// It provides an abstraction over the data access layer.
+type CommentRepository struct {
+	db *sql.DB
+}
+
+// NewCommentRepository creates a new CommentRepository with the given database connection.
+func NewCommentRepository(db *sql.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// GetByPostID retrieves all comments for a post as a flat list, ordered
+// chronologically (oldest first) so replies are never returned ahead of the
+// comment they reply to.
+func (r *CommentRepository) GetByPostID(postID string) ([]models.Comment, error) {
+	rows, err := r.db.Query(`
+		SELECT id, post_id, author_id, content, created_at, parent_comment_id
+		FROM comments
+		WHERE post_id = ?
+		ORDER BY created_at ASC, rowid ASC`, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := make([]models.Comment, 0)
+	for rows.Next() {
+		var c models.Comment
+		var parentID sql.NullString
+		if err := rows.Scan(&c.ID, &c.PostID, &c.AuthorID, &c.Content, &c.CreatedAt, &parentID); err != nil {
+			return nil, err
+		}
+		if parentID.Valid {
+			c.ParentCommentID = &parentID.String
+		}
+		comments = append(comments, c)
+	}
+
+	return comments, rows.Err()
+}
+
+// Create inserts a new comment into the database.
+// It uses a prepared statement to prevent SQL injection vulnerabilities.
+func (r *CommentRepository) Create(comment *models.Comment) error {
+	stmt, err := r.db.Prepare(`
+		INSERT INTO comments(id, post_id, author_id, content, created_at, parent_comment_id)
+		VALUES(?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(comment.ID, comment.PostID, comment.AuthorID, comment.Content, comment.CreatedAt, comment.ParentCommentID)
+	return err
+}
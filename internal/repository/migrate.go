@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+const migrationsDir = "migrations"
+
+// RunMigrations applies the embedded .sql files under migrations/, in
+// filename order, that aren't yet recorded in schema_migrations. Each
+// migration runs inside its own transaction, so a failing migration leaves
+// the schema at the last successfully applied version rather than half
+// applied.
+func RunMigrations(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		"version" INTEGER NOT NULL PRIMARY KEY,
+		"applied_at" TEXT NOT NULL
+	);`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	entries, err := migrationFiles.ReadDir(migrationsDir)
+	if err != nil {
+		return fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version, err := migrationVersion(name)
+		if err != nil {
+			return err
+		}
+
+		var alreadyApplied bool
+		if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = ?)", version).Scan(&alreadyApplied); err != nil {
+			return fmt.Errorf("failed to check migration %s: %w", name, err)
+		}
+		if alreadyApplied {
+			continue
+		}
+
+		if err := applyMigration(db, version, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrationVersion parses the numeric prefix of a migration filename (e.g.
+// "0004_create_posts_fts.sql" -> 4). The version recorded in
+// schema_migrations comes from this prefix rather than the file's position
+// in the sorted list, so inserting or renaming a migration can't silently
+// remap already-applied versions.
+func migrationVersion(name string) (int, error) {
+	prefix, _, ok := strings.Cut(name, "_")
+	if !ok {
+		return 0, fmt.Errorf("migration %s has no numeric prefix", name)
+	}
+
+	version, err := strconv.Atoi(prefix)
+	if err != nil {
+		return 0, fmt.Errorf("migration %s has an invalid numeric prefix: %w", name, err)
+	}
+
+	return version, nil
+}
+
+// applyMigration runs a single migration file and records it in
+// schema_migrations, all inside one transaction.
+func applyMigration(db *sql.DB, version int, name string) error {
+	contents, err := migrationFiles.ReadFile(path.Join(migrationsDir, name))
+	if err != nil {
+		return fmt.Errorf("failed to read migration %s: %w", name, err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for migration %s: %w", name, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(string(contents)); err != nil {
+		return fmt.Errorf("failed to apply migration %s: %w", name, err)
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations(version, applied_at) VALUES (?, ?)",
+		version, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -2,10 +2,17 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
 
 	"github.com/hajime110419/go-bbs-api/internal/models"
 )
 
+// ErrInvalidSearchQuery is returned by GetPage when params.Query is not
+// valid FTS5 query syntax (e.g. an unbalanced quote), so callers can report
+// it as a client error instead of an internal one.
+var ErrInvalidSearchQuery = fmt.Errorf("invalid search query")
+
 // PostRepository handles all database operations for posts.
 // It provides an abstraction over the data access layer.
 type PostRepository struct {
@@ -17,37 +24,148 @@ func NewPostRepository(db *sql.DB) *PostRepository {
 	return &PostRepository{db: db}
 }
 
-// GetAll retrieves all posts from the database, ordered by creation time (descending).
+// ListParams filters and paginates a call to GetPage.
+type ListParams struct {
+	// Limit is the maximum number of posts to return.
+	Limit int
+	// Cursor, when non-zero, restricts results to rowids below it (the
+	// rowid of the last post from the previous page).
+	Cursor int64
+	// Query, when non-empty, is matched against title and content via the
+	// posts_fts FTS5 index.
+	Query string
+	// Author, when non-empty, restricts results to posts by that author ID.
+	Author string
+}
+
+// Page is a single keyset-paginated slice of posts.
+type Page struct {
+	Posts []models.Post
+	// NextCursor is the rowid to pass as Cursor to fetch the next page, or
+	// zero if there is no further page.
+	NextCursor int64
+}
+
+// GetPage retrieves a page of posts, most recent first, joining in each
+// post's author display name. It never materializes more than Limit+1 rows,
+// so the server can never be made to load an arbitrarily large result set.
 // "rowid" is an implicit auto-incrementing column in SQLite. Ordering by it
 // in descending order retrieves the most recent posts first.
-func (r *PostRepository) GetAll() ([]models.Post, error) {
-	rows, err := r.db.Query("SELECT id, title, content FROM posts ORDER BY rowid DESC")
+func (r *PostRepository) GetPage(params ListParams) (*Page, error) {
+	var query strings.Builder
+	var args []any
+
+	query.WriteString(`
+		SELECT posts.rowid, posts.id, posts.title, posts.content, posts.content_raw, posts.author_id, COALESCE(users.username, '')
+		FROM posts
+		LEFT JOIN users ON users.id = posts.author_id`)
+
+	var conditions []string
+	if params.Query != "" {
+		conditions = append(conditions, "posts.rowid IN (SELECT rowid FROM posts_fts WHERE posts_fts MATCH ?)")
+		args = append(args, params.Query)
+	}
+	if params.Author != "" {
+		conditions = append(conditions, "posts.author_id = ?")
+		args = append(args, params.Author)
+	}
+	if params.Cursor > 0 {
+		conditions = append(conditions, "posts.rowid < ?")
+		args = append(args, params.Cursor)
+	}
+	if len(conditions) > 0 {
+		query.WriteString(" WHERE " + strings.Join(conditions, " AND "))
+	}
+
+	query.WriteString(" ORDER BY posts.rowid DESC LIMIT ?")
+	args = append(args, params.Limit+1)
+
+	rows, err := r.db.Query(query.String(), args...)
 	if err != nil {
+		if params.Query != "" && (strings.Contains(err.Error(), "fts5: syntax error") ||
+			strings.Contains(err.Error(), "unterminated string")) {
+			return nil, ErrInvalidSearchQuery
+		}
 		return nil, err
 	}
 	defer rows.Close()
 
-	posts := make([]models.Post, 0)
+	posts := make([]models.Post, 0, params.Limit)
+	rowIDs := make([]int64, 0, params.Limit)
 	for rows.Next() {
+		var rowID int64
 		var p models.Post
-		if err := rows.Scan(&p.ID, &p.Title, &p.Content); err != nil {
+		if err := rows.Scan(&rowID, &p.ID, &p.Title, &p.Content, &p.ContentRaw, &p.AuthorID, &p.Author); err != nil {
 			return nil, err
 		}
 		posts = append(posts, p)
+		rowIDs = append(rowIDs, rowID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return posts, rows.Err()
+	page := &Page{Posts: posts}
+	if len(posts) > params.Limit {
+		page.Posts = posts[:params.Limit]
+		page.NextCursor = rowIDs[params.Limit-1]
+	}
+
+	return page, nil
 }
 
 // Create inserts a new post into the database.
 // It uses a prepared statement to prevent SQL injection vulnerabilities.
 func (r *PostRepository) Create(post *models.Post) error {
-	stmt, err := r.db.Prepare("INSERT INTO posts(id, title, content) VALUES(?, ?, ?)")
+	stmt, err := r.db.Prepare("INSERT INTO posts(id, title, content, content_raw, author_id) VALUES(?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(post.ID, post.Title, post.Content, post.ContentRaw, post.AuthorID)
+	return err
+}
+
+// GetByID retrieves a single post by ID, joining in its author's display
+// name. It returns a nil post, with no error, when no post matches.
+func (r *PostRepository) GetByID(id string) (*models.Post, error) {
+	var p models.Post
+	err := r.db.QueryRow(`
+		SELECT posts.id, posts.title, posts.content, posts.content_raw, posts.author_id, COALESCE(users.username, '')
+		FROM posts
+		LEFT JOIN users ON users.id = posts.author_id
+		WHERE posts.id = ?`, id).Scan(&p.ID, &p.Title, &p.Content, &p.ContentRaw, &p.AuthorID, &p.Author)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Update overwrites a post's title, sanitized content, and raw content.
+// It uses a prepared statement to prevent SQL injection vulnerabilities.
+func (r *PostRepository) Update(post *models.Post) error {
+	stmt, err := r.db.Prepare("UPDATE posts SET title = ?, content = ?, content_raw = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(post.Title, post.Content, post.ContentRaw, post.ID)
+	return err
+}
+
+// Delete removes a post by ID.
+func (r *PostRepository) Delete(id string) error {
+	stmt, err := r.db.Prepare("DELETE FROM posts WHERE id = ?")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(post.ID, post.Title, post.Content)
+	_, err = stmt.Exec(id)
 	return err
 }
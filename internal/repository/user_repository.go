@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/hajime110419/go-bbs-api/internal/models"
+)
+
+// UserRepository handles all database operations for users.
+// It provides an abstraction over the data access layer.
+type UserRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new UserRepository with the given database connection.
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Create inserts a new user into the database, storing only the hash of its
+// bearer token. It uses a prepared statement to prevent SQL injection
+// vulnerabilities.
+func (r *UserRepository) Create(user *models.User, tokenHash string) error {
+	stmt, err := r.db.Prepare("INSERT INTO users(id, username, token_hash) VALUES(?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(user.ID, user.Username, tokenHash)
+	return err
+}
+
+// GetByTokenHash looks up the user whose bearer token hashes to tokenHash.
+// It returns a nil user, with no error, when no user matches.
+func (r *UserRepository) GetByTokenHash(tokenHash string) (*models.User, error) {
+	var u models.User
+	err := r.db.QueryRow("SELECT id, username FROM users WHERE token_hash = ?", tokenHash).Scan(&u.ID, &u.Username)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
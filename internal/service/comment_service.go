@@ -0,0 +1,113 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hajime110419/go-bbs-api/internal/models"
+	"github.com/hajime110419/go-bbs-api/internal/repository"
+	"github.com/hajime110419/go-bbs-api/internal/utils"
+)
+
+type CommentService struct {
+	repo     *repository.CommentRepository
+	posts    *repository.PostRepository
+	renderer *utils.Renderer
+}
+
+func NewCommentService(db *sql.DB) *CommentService {
+	return &CommentService{
+		repo:     repository.NewCommentRepository(db),
+		posts:    repository.NewPostRepository(db),
+		renderer: utils.NewRenderer(),
+	}
+}
+
+// GetByPostID retrieves all comments for a post as a flat, chronologically
+// ordered list.
+func (s *CommentService) GetByPostID(postID string) ([]models.Comment, error) {
+	return s.repo.GetByPostID(postID)
+}
+
+// GetTree retrieves all comments for a post nested one level deep: each
+// top-level comment carries in Replies every reply in its thread, including
+// replies to replies. A reply whose
+// parent is missing (e.g. deleted) is surfaced as top-level so it isn't lost.
+func (s *CommentService) GetTree(postID string) ([]*models.Comment, error) {
+	flat, err := s.repo.GetByPostID(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*models.Comment, len(flat))
+	for i := range flat {
+		byID[flat[i].ID] = &flat[i]
+	}
+
+	roots := make([]*models.Comment, 0)
+	for i := range flat {
+		c := &flat[i]
+		parent, ok := byID[derefString(c.ParentCommentID)]
+		if c.ParentCommentID == nil || !ok {
+			roots = append(roots, c)
+			continue
+		}
+		for parent.ParentCommentID != nil {
+			grandparent, ok := byID[*parent.ParentCommentID]
+			if !ok {
+				break
+			}
+			parent = grandparent
+		}
+		parent.Replies = append(parent.Replies, c)
+	}
+
+	return roots, nil
+}
+
+// CreateComment handles business logic for creating a comment. Content is
+// parsed as Markdown and sanitized before being persisted.
+func (s *CommentService) CreateComment(postID, authorID, content string, parentCommentID *string) (*models.Comment, error) {
+	post, err := s.posts.GetByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil {
+		return nil, ErrPostNotFound
+	}
+
+	sanitizedContent, err := s.renderer.Render(content)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		ID:              uuid.New().String(),
+		PostID:          postID,
+		AuthorID:        authorID,
+		Content:         sanitizedContent,
+		CreatedAt:       time.Now().UTC().Format(time.RFC3339),
+		ParentCommentID: parentCommentID,
+	}
+
+	if comment.Content == "" {
+		return nil, ErrInvalidComment
+	}
+
+	if err := s.repo.Create(comment); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+var ErrInvalidComment = fmt.Errorf("invalid comment: content required")
@@ -2,7 +2,9 @@ package service
 
 import (
 	"database/sql"
+	"encoding/base64"
 	"fmt"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/hajime110419/go-bbs-api/internal/models"
@@ -10,27 +12,118 @@ import (
 	"github.com/hajime110419/go-bbs-api/internal/utils"
 )
 
+// DefaultPageSize is the number of posts returned by ListPosts when the
+// caller doesn't request a specific limit.
+const DefaultPageSize = 20
+
+// MaxPageSize caps how many posts a single ListPosts call can return, so a
+// client can't force the server to materialize an arbitrarily large result
+// set via a huge ?limit=.
+const MaxPageSize = 100
+
 type PostService struct {
-	repo *repository.PostRepository
+	repo     *repository.PostRepository
+	renderer *utils.Renderer
 }
 
 func NewPostService(db *sql.DB) *PostService {
 	return &PostService{
-		repo: repository.NewPostRepository(db),
+		repo:     repository.NewPostRepository(db),
+		renderer: utils.NewRenderer(),
+	}
+}
+
+// ListParams filters and paginates a call to ListPosts.
+type ListParams struct {
+	// Limit is the maximum number of posts to return. Zero means
+	// DefaultPageSize.
+	Limit int
+	// Cursor is the opaque, base64-encoded cursor returned as the previous
+	// page's NextCursor, or empty for the first page.
+	Cursor string
+	// Query, when non-empty, searches title and content full-text.
+	Query string
+	// Author, when non-empty, restricts results to posts by that author ID.
+	Author string
+}
+
+// Page is a single page of posts along with the cursor to fetch the next one.
+type Page struct {
+	Posts []models.Post
+	// NextCursor is empty when there is no further page.
+	NextCursor string
+}
+
+// ListPosts retrieves a page of posts matching the given filters.
+func (s *PostService) ListPosts(params ListParams) (*Page, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	var cursor int64
+	if params.Cursor != "" {
+		decoded, err := decodeCursor(params.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		cursor = decoded
+	}
+
+	repoPage, err := s.repo.GetPage(repository.ListParams{
+		Limit:  limit,
+		Cursor: cursor,
+		Query:  params.Query,
+		Author: params.Author,
+	})
+	if err != nil {
+		if err == repository.ErrInvalidSearchQuery {
+			return nil, ErrInvalidQuery
+		}
+		return nil, err
+	}
+
+	page := &Page{Posts: repoPage.Posts}
+	if repoPage.NextCursor > 0 {
+		page.NextCursor = encodeCursor(repoPage.NextCursor)
 	}
+
+	return page, nil
 }
 
-// GetAllPosts retrieves all posts, applying business rules
-func (s *PostService) GetAllPosts() ([]models.Post, error) {
-	return s.repo.GetAll()
+// encodeCursor turns a rowid into the opaque cursor clients pass back via
+// ?cursor=.
+func encodeCursor(rowID int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.FormatInt(rowID, 10)))
+}
+
+// decodeCursor reverses encodeCursor.
+func decodeCursor(cursor string) (int64, error) {
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(raw), 10, 64)
 }
 
-// CreatePost handles business logic for creating a post
-func (s *PostService) CreatePost(title, content string) (*models.Post, error) {
+// CreatePost handles business logic for creating a post. Content is parsed
+// as Markdown and sanitized before being persisted alongside the raw input,
+// which is kept so the post can be edited later.
+func (s *PostService) CreatePost(title, content, authorID string) (*models.Post, error) {
+	sanitizedContent, err := s.renderer.Render(content)
+	if err != nil {
+		return nil, err
+	}
+
 	post := &models.Post{
-		ID:      uuid.New().String(),
-		Title:   utils.Sanitize(title),
-		Content: utils.Sanitize(content),
+		ID:         uuid.New().String(),
+		Title:      utils.Sanitize(title),
+		Content:    sanitizedContent,
+		ContentRaw: content,
+		AuthorID:   authorID,
 	}
 
 	if post.Title == "" || post.Content == "" {
@@ -44,4 +137,72 @@ func (s *PostService) CreatePost(title, content string) (*models.Post, error) {
 	return post, nil
 }
 
-var ErrInvalidPost = fmt.Errorf("invalid post: title and content required")
+// GetPost retrieves a single post by ID.
+func (s *PostService) GetPost(id string) (*models.Post, error) {
+	post, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil {
+		return nil, ErrPostNotFound
+	}
+	return post, nil
+}
+
+// UpdatePost overwrites a post's title and content, enforcing that only the
+// post's author may edit it.
+func (s *PostService) UpdatePost(id, authorID, title, content string) (*models.Post, error) {
+	post, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil {
+		return nil, ErrPostNotFound
+	}
+	if post.AuthorID != authorID {
+		return nil, ErrForbidden
+	}
+
+	sanitizedContent, err := s.renderer.Render(content)
+	if err != nil {
+		return nil, err
+	}
+
+	post.Title = utils.Sanitize(title)
+	post.Content = sanitizedContent
+	post.ContentRaw = content
+	if post.Title == "" || post.Content == "" {
+		return nil, ErrInvalidPost
+	}
+
+	if err := s.repo.Update(post); err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
+
+// DeletePost removes a post, enforcing that only the post's author may
+// delete it.
+func (s *PostService) DeletePost(id, authorID string) error {
+	post, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if post == nil {
+		return ErrPostNotFound
+	}
+	if post.AuthorID != authorID {
+		return ErrForbidden
+	}
+
+	return s.repo.Delete(id)
+}
+
+var (
+	ErrInvalidPost   = fmt.Errorf("invalid post: title and content required")
+	ErrPostNotFound  = fmt.Errorf("post not found")
+	ErrForbidden     = fmt.Errorf("not authorized to modify this post")
+	ErrInvalidCursor = fmt.Errorf("invalid cursor")
+	ErrInvalidQuery  = fmt.Errorf("invalid search query")
+)
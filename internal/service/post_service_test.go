@@ -0,0 +1,132 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/hajime110419/go-bbs-api/internal/repository"
+)
+
+// newTestDB opens a fresh, migrated in-memory database for a single test.
+// Max open connections is capped at 1 so every query sees the same
+// in-memory SQLite instance.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := repository.InitDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to initialize test database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestEncodeDecodeCursor_RoundTrip(t *testing.T) {
+	for _, rowID := range []int64{1, 42, 1_000_000} {
+		cursor := encodeCursor(rowID)
+
+		got, err := decodeCursor(cursor)
+		if err != nil {
+			t.Fatalf("decodeCursor(%q): unexpected error: %v", cursor, err)
+		}
+		if got != rowID {
+			t.Fatalf("decodeCursor(encodeCursor(%d)) = %d, want %d", rowID, got, rowID)
+		}
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	if _, err := decodeCursor("not-valid-base64!!"); err == nil {
+		t.Fatal("expected an error decoding a malformed cursor, got nil")
+	}
+}
+
+func TestListPosts_NextCursorPagination(t *testing.T) {
+	db := newTestDB(t)
+	posts := NewPostService(db)
+	users := NewUserService(db)
+
+	author, _, err := users.Register("alice")
+	if err != nil {
+		t.Fatalf("Register: unexpected error: %v", err)
+	}
+
+	const total = 3
+	for i := 0; i < total; i++ {
+		if _, err := posts.CreatePost("title", "some content", author.ID); err != nil {
+			t.Fatalf("CreatePost: unexpected error: %v", err)
+		}
+	}
+
+	page, err := posts.ListPosts(ListParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListPosts: unexpected error: %v", err)
+	}
+	if len(page.Posts) != 2 {
+		t.Fatalf("expected 2 posts on the first page, got %d", len(page.Posts))
+	}
+	if page.NextCursor == "" {
+		t.Fatal("expected a non-empty NextCursor when more posts remain")
+	}
+
+	nextPage, err := posts.ListPosts(ListParams{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("ListPosts with cursor: unexpected error: %v", err)
+	}
+	if len(nextPage.Posts) != total-2 {
+		t.Fatalf("expected %d posts on the second page, got %d", total-2, len(nextPage.Posts))
+	}
+	if nextPage.NextCursor != "" {
+		t.Fatal("expected an empty NextCursor once all posts are exhausted")
+	}
+}
+
+func TestUpdatePost_RejectsNonAuthor(t *testing.T) {
+	db := newTestDB(t)
+	posts := NewPostService(db)
+	users := NewUserService(db)
+
+	author, _, err := users.Register("alice")
+	if err != nil {
+		t.Fatalf("Register: unexpected error: %v", err)
+	}
+	impostor, _, err := users.Register("mallory")
+	if err != nil {
+		t.Fatalf("Register: unexpected error: %v", err)
+	}
+
+	post, err := posts.CreatePost("title", "original content", author.ID)
+	if err != nil {
+		t.Fatalf("CreatePost: unexpected error: %v", err)
+	}
+
+	if _, err := posts.UpdatePost(post.ID, impostor.ID, "new title", "new content"); err != ErrForbidden {
+		t.Fatalf("UpdatePost by non-author: got error %v, want %v", err, ErrForbidden)
+	}
+}
+
+func TestDeletePost_RejectsNonAuthor(t *testing.T) {
+	db := newTestDB(t)
+	posts := NewPostService(db)
+	users := NewUserService(db)
+
+	author, _, err := users.Register("alice")
+	if err != nil {
+		t.Fatalf("Register: unexpected error: %v", err)
+	}
+	impostor, _, err := users.Register("mallory")
+	if err != nil {
+		t.Fatalf("Register: unexpected error: %v", err)
+	}
+
+	post, err := posts.CreatePost("title", "original content", author.ID)
+	if err != nil {
+		t.Fatalf("CreatePost: unexpected error: %v", err)
+	}
+
+	if err := posts.DeletePost(post.ID, impostor.ID); err != ErrForbidden {
+		t.Fatalf("DeletePost by non-author: got error %v, want %v", err, ErrForbidden)
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hajime110419/go-bbs-api/internal/auth"
+	"github.com/hajime110419/go-bbs-api/internal/models"
+	"github.com/hajime110419/go-bbs-api/internal/repository"
+)
+
+type UserService struct {
+	repo *repository.UserRepository
+}
+
+func NewUserService(db *sql.DB) *UserService {
+	return &UserService{
+		repo: repository.NewUserRepository(db),
+	}
+}
+
+// Register creates a new user account and returns it along with a freshly
+// minted bearer token. The token is only ever available here; the
+// repository persists just its hash.
+func (s *UserService) Register(username string) (*models.User, string, error) {
+	if username == "" {
+		return nil, "", ErrInvalidUser
+	}
+
+	token, err := auth.GenerateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	user := &models.User{
+		ID:       uuid.New().String(),
+		Username: username,
+	}
+
+	if err := s.repo.Create(user, auth.HashToken(token)); err != nil {
+		if isUniqueConstraintErr(err) {
+			return nil, "", ErrUsernameTaken
+		}
+		return nil, "", err
+	}
+
+	return user, token, nil
+}
+
+// Authenticate looks up the user associated with a bearer token. It returns
+// a nil user, with no error, when the token is empty or unknown.
+func (s *UserService) Authenticate(token string) (*models.User, error) {
+	if token == "" {
+		return nil, nil
+	}
+	return s.repo.GetByTokenHash(auth.HashToken(token))
+}
+
+func isUniqueConstraintErr(err error) bool {
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+var (
+	ErrInvalidUser   = fmt.Errorf("invalid user: username required")
+	ErrUsernameTaken = fmt.Errorf("username already taken")
+)
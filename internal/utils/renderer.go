@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+)
+
+// Renderer turns raw user-submitted Markdown into sanitized HTML safe to
+// embed directly in a page: it parses CommonMark via goldmark, then strips
+// unsafe tags and attributes via bluemonday's UGC policy, which permits
+// links, inline and fenced code, blockquotes, and basic emphasis.
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+}
+
+// NewRenderer creates a Renderer using goldmark's default CommonMark
+// configuration and bluemonday's UGC policy.
+func NewRenderer() *Renderer {
+	return &Renderer{
+		md:     goldmark.New(),
+		policy: bluemonday.UGCPolicy(),
+	}
+}
+
+// Render parses raw as CommonMark and returns sanitized HTML. Input that is
+// entirely unsafe markup (e.g. a bare <script> tag) renders down to an
+// empty string.
+func (r *Renderer) Render(raw string) (string, error) {
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(raw), &buf); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(r.policy.Sanitize(buf.String())), nil
+}